@@ -2,7 +2,7 @@ package platform
 
 import (
 	"fmt"
-	"os"
+	"time"
 
 	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
 	"github.com/loft-sh/api/v4/pkg/product"
@@ -31,11 +31,16 @@ var (
 // BackupCmd holds the cmd flags
 type BackupCmd struct {
 	*flags.GlobalFlags
-	Log       log.Logger
-	Namespace string
-	Filename  string
-	Skip      []string
-	cfg       *config.CLI
+	Log                log.Logger
+	Namespace          string
+	Filename           string
+	Destination        string
+	KMS                string
+	Skip               []string
+	KeepLast           int
+	KeepDays           int
+	MetricsPushgateway string
+	cfg                *config.CLI
 }
 
 // newBackupCmd creates a new command
@@ -72,12 +77,26 @@ vcluster platform backup
 
 	c.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, "What resources the backup should skip. Valid options are: users, teams, accesskeys, sharedsecrets, clusters and clusteraccounttemplates")
 	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", product.Replace("The namespace vCluster platform was installed into"))
-	c.Flags().StringVar(&cmd.Filename, "filename", "backup.yaml", "The filename to write the backup to")
+	c.Flags().StringVar(&cmd.Filename, "filename", "backup.yaml", "The filename to write the backup to. Ignored if --destination is set")
+	c.Flags().StringVar(&cmd.Destination, "destination", "", "Where to write the backup to. Supports file://, s3://, gs:// and azure:// URLs. Defaults to writing --filename locally")
+	c.Flags().StringVar(&cmd.KMS, "kms", "", "Envelope-encrypt the backup before writing it. Supports aws-kms://<key-arn>, gcp-kms://<key-name>, azure-kv://<vault>/keys/<name> and passphrase:// URIs")
+	c.Flags().IntVar(&cmd.KeepLast, "keep-last", 0, "Keep at least this many backups at the destination, deleting older ones. Requires --destination and a 0 value disables count-based retention")
+	c.Flags().IntVar(&cmd.KeepDays, "keep-days", 0, "Delete backups older than this many days at the destination. Requires --destination and a 0 value disables age-based retention")
+	c.Flags().StringVar(&cmd.MetricsPushgateway, "metrics-pushgateway", "", "Push loft_backup_* metrics to this Prometheus Pushgateway URL after the run finishes. Used by 'platform backup schedule'")
+
+	c.AddCommand(newScheduleCmd(globalFlags, cfg))
 	return c
 }
 
 // run executes the functionality
 func (cmd *BackupCmd) run(cobraCmd *cobra.Command) error {
+	start := time.Now()
+	err := cmd.doRun(cobraCmd)
+	cmd.reportRun(cobraCmd, time.Since(start), err)
+	return err
+}
+
+func (cmd *BackupCmd) doRun(cobraCmd *cobra.Command) error {
 	// first load the kube config
 	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
 
@@ -123,13 +142,80 @@ func (cmd *BackupCmd) run(cobraCmd *cobra.Command) error {
 		return err
 	}
 
-	// create a file
-	cmd.Log.Infof("Writing backup to %s...", cmd.Filename)
-	err = os.WriteFile(cmd.Filename, backupBytes, 0644)
+	encryptor, err := backup.NewEncryptor(cmd.KMS)
 	if err != nil {
 		return err
 	}
+	if encryptor != nil {
+		backupBytes, err = encryptor.Encrypt(ctx, backupBytes)
+		if err != nil {
+			return fmt.Errorf("encrypt backup: %w", err)
+		}
+	}
+
+	destination, err := backup.NewDestination(cmd.Destination, cmd.Filename)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Infof("Writing backup...")
+	location, err := destination.Write(ctx, backupBytes)
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Donef("Wrote backup to %s", location)
+
+	if cmd.KeepLast > 0 || cmd.KeepDays > 0 {
+		pruned, err := backup.Prune(ctx, destination, backup.RetentionOptions{KeepLast: cmd.KeepLast, KeepDays: cmd.KeepDays}, func(msg string) {
+			cmd.Log.Info(msg)
+		})
+		if err != nil {
+			return fmt.Errorf("enforce retention: %w", err)
+		}
+		if pruned > 0 {
+			cmd.Log.Donef("Pruned %d backup(s) outside the retention window", pruned)
+		}
+	}
 
-	cmd.Log.Donef("Wrote backup to %s", cmd.Filename)
 	return nil
 }
+
+// reportRun pushes metrics and records a Kubernetes Event for a backup
+// run, best-effort. It exists so that `platform backup schedule`'s CronJob
+// gets the observability Velero-style scheduled backups are expected to
+// have, without a one-shot `platform backup` invocation needing any of it.
+func (cmd *BackupCmd) reportRun(cobraCmd *cobra.Command, duration time.Duration, runErr error) {
+	// a one-shot `platform backup` run has nowhere to push metrics/events
+	// to and isn't expected to have any; --metrics-pushgateway is what
+	// `platform backup schedule` sets on the CronJob it installs to opt in.
+	if cmd.MetricsPushgateway == "" {
+		return
+	}
+
+	if err := backup.PushMetrics(cmd.MetricsPushgateway, "loft_backup", backup.RunMetrics{
+		Success:          runErr == nil,
+		DurationSeconds:  duration.Seconds(),
+		SuccessTimestamp: time.Now().Unix(),
+	}); err != nil {
+		cmd.Log.Warnf("push backup metrics: %v", err)
+	}
+
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	kubeConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return
+	}
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return
+	}
+
+	message := "backup completed successfully"
+	if runErr != nil {
+		message = fmt.Sprintf("backup failed: %v", runErr)
+	}
+	if err := backup.RecordEvent(cobraCmd.Context(), kubeClient, cmd.Namespace, runErr == nil, message); err != nil {
+		cmd.Log.Warnf("record backup event: %v", err)
+	}
+}
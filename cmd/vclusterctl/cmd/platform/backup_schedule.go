@@ -0,0 +1,183 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// defaultBackupImage is the image the schedule's CronJob runs `vcluster
+// platform backup` from. It tracks the vcluster-cli `latest` tag rather
+// than the installing CLI's own version, so a scheduled backup can pick up
+// fixes between CronJob runs; pass --image to pin it to a specific version
+// instead.
+const defaultBackupImage = "ghcr.io/loft-sh/vcluster-cli:latest"
+
+// ScheduleCmd holds the cmd flags
+type ScheduleCmd struct {
+	*flags.GlobalFlags
+	Log                log.Logger
+	Namespace          string
+	Cron               string
+	Image              string
+	Destination        string
+	KMS                string
+	Skip               []string
+	KeepLast           int
+	KeepDays           int
+	MetricsPushgateway string
+	cfg                *config.CLI
+}
+
+// newScheduleCmd creates a new command
+func newScheduleCmd(globalFlags *flags.GlobalFlags, cfg *config.CLI) *cobra.Command {
+	cmd := &ScheduleCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+		cfg:         cfg,
+	}
+
+	description := product.ReplaceWithHeader("backup schedule", `
+Schedule installs a CronJob that periodically backs up the vCluster platform management plane
+
+Example:
+vcluster platform backup schedule --cron "0 * * * *" --destination s3://my-bucket
+########################################################
+	`)
+
+	c := &cobra.Command{
+		Use:   "schedule",
+		Short: product.Replace("Install a recurring vCluster platform management plane backup"),
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			_, err := platform.NewClientFromConfig(cobraCmd.Context(), cmd.cfg)
+			if err != nil {
+				return fmt.Errorf("create vCluster platform client: %w", err)
+			}
+
+			return cmd.run(cobraCmd)
+		},
+	}
+
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", product.Replace("The namespace vCluster platform was installed into"))
+	c.Flags().StringVar(&cmd.Cron, "cron", "0 * * * *", "The cron schedule to run backups on")
+	c.Flags().StringVar(&cmd.Image, "image", defaultBackupImage, "The vcluster CLI image the CronJob runs 'platform backup' from")
+	c.Flags().StringVar(&cmd.Destination, "destination", "", "Where each scheduled backup should be written to, e.g. s3://my-bucket")
+	c.Flags().StringVar(&cmd.KMS, "kms", "", "Envelope-encrypt each scheduled backup with this provider")
+	c.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, "What resources each scheduled backup should skip")
+	c.Flags().IntVar(&cmd.KeepLast, "keep-last", 7, "Keep at least this many scheduled backups at the destination")
+	c.Flags().IntVar(&cmd.KeepDays, "keep-days", 30, "Delete scheduled backups older than this many days at the destination")
+	c.Flags().StringVar(&cmd.MetricsPushgateway, "metrics-pushgateway", "", "Push loft_backup_* metrics to this Prometheus Pushgateway URL after each scheduled run, and record a Kubernetes Event")
+	return c
+}
+
+// run executes the functionality
+func (cmd *ScheduleCmd) run(cobraCmd *cobra.Command) error {
+	if cmd.Destination == "" {
+		return fmt.Errorf("--destination is required so scheduled backups don't pile up on the CronJob pod's ephemeral disk")
+	}
+
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	kubeConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx := cobraCmd.Context()
+	if err := cmd.ensureRBAC(ctx, kubeClient); err != nil {
+		return err
+	}
+
+	cronJob := backup.NewCronJob(backup.ScheduleOptions{
+		Cron:               cmd.Cron,
+		Image:              cmd.Image,
+		Namespace:          cmd.Namespace,
+		Destination:        cmd.Destination,
+		KMS:                cmd.KMS,
+		Skip:               cmd.Skip,
+		KeepLast:           cmd.KeepLast,
+		KeepDays:           cmd.KeepDays,
+		MetricsPushgateway: cmd.MetricsPushgateway,
+	})
+
+	existing, err := kubeClient.BatchV1().CronJobs(cmd.Namespace).Get(ctx, backup.CronJobName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if _, err := kubeClient.BatchV1().CronJobs(cmd.Namespace).Create(ctx, cronJob, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create backup schedule: %w", err)
+		}
+		cmd.Log.Donef("Installed backup schedule %s/%s (%s)", cmd.Namespace, backup.CronJobName, cmd.Cron)
+	case err != nil:
+		return fmt.Errorf("get existing backup schedule: %w", err)
+	default:
+		cronJob.ResourceVersion = existing.ResourceVersion
+		if _, err := kubeClient.BatchV1().CronJobs(cmd.Namespace).Update(ctx, cronJob, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update backup schedule: %w", err)
+		}
+		cmd.Log.Donef("Updated backup schedule %s/%s (%s)", cmd.Namespace, backup.CronJobName, cmd.Cron)
+	}
+
+	return nil
+}
+
+// ensureRBAC installs the ServiceAccount and ClusterRole(Binding) the
+// schedule's CronJob runs as, so that it can actually list the resources
+// `vcluster platform backup` backs up instead of failing with forbidden
+// errors on every run.
+func (cmd *ScheduleCmd) ensureRBAC(ctx context.Context, kubeClient kubernetes.Interface) error {
+	serviceAccount := backup.NewServiceAccount(cmd.Namespace)
+	if _, err := kubeClient.CoreV1().ServiceAccounts(cmd.Namespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create backup service account: %w", err)
+	}
+
+	clusterRole := backup.NewClusterRole()
+	existingRole, err := kubeClient.RbacV1().ClusterRoles().Get(ctx, backup.ClusterRoleName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if _, err := kubeClient.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create backup cluster role: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("get existing backup cluster role: %w", err)
+	default:
+		clusterRole.ResourceVersion = existingRole.ResourceVersion
+		if _, err := kubeClient.RbacV1().ClusterRoles().Update(ctx, clusterRole, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update backup cluster role: %w", err)
+		}
+	}
+
+	clusterRoleBinding := backup.NewClusterRoleBinding(cmd.Namespace)
+	existingBinding, err := kubeClient.RbacV1().ClusterRoleBindings().Get(ctx, backup.ClusterRoleName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		if _, err := kubeClient.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create backup cluster role binding: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("get existing backup cluster role binding: %w", err)
+	default:
+		clusterRoleBinding.ResourceVersion = existingBinding.ResourceVersion
+		if _, err := kubeClient.RbacV1().ClusterRoleBindings().Update(ctx, clusterRoleBinding, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update backup cluster role binding: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package platform
+
+import (
+	"fmt"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/cli"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/spf13/cobra"
+)
+
+// RegisterCmd holds the cmd flags
+type RegisterCmd struct {
+	*flags.GlobalFlags
+	Log         log.Logger
+	FromCommand string
+	Namespace   string
+	Restart     bool
+}
+
+// newRegisterCmd creates a new command
+func newRegisterCmd(globalFlags *flags.GlobalFlags) *cobra.Command {
+	cmd := &RegisterCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+	}
+
+	description := product.ReplaceWithHeader("register", `
+Register onboards the vCluster in the current kube context to the vCluster platform,
+using a one-shot registration command generated by
+'vcluster platform add vcluster --print-registration-command'.
+
+Example:
+vcluster platform register --from-command <base64> my-vcluster
+########################################################
+	`)
+
+	c := &cobra.Command{
+		Use:   "register",
+		Short: product.Replace("Register a vCluster with the platform using a registration command"),
+		Long:  description,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return cmd.run(cobraCmd, args[0])
+		},
+	}
+
+	c.Flags().StringVar(&cmd.FromCommand, "from-command", "", "The base64 registration command printed by 'platform add vcluster --print-registration-command'")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "", "The namespace the vCluster is running in. Defaults to the current kube context's namespace")
+	c.Flags().BoolVar(&cmd.Restart, "restart", false, "Restart the vCluster after registering it")
+	_ = c.MarkFlagRequired("from-command")
+	return c
+}
+
+// run executes the functionality
+func (cmd *RegisterCmd) run(cobraCmd *cobra.Command, vClusterName string) error {
+	if cmd.FromCommand == "" {
+		return fmt.Errorf("--from-command is required")
+	}
+
+	namespace := cmd.Namespace
+	if namespace == "" {
+		namespace = cmd.GlobalFlags.Namespace
+	}
+	if namespace == "" {
+		return fmt.Errorf("--namespace is required when it cannot be determined from the current kube context")
+	}
+
+	return cli.RegisterVCluster(cobraCmd.Context(), cmd.FromCommand, namespace, vClusterName, cmd.Restart, cmd.Log)
+}
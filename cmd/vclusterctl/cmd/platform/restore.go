@@ -0,0 +1,157 @@
+package platform
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/loft-sh/api/v4/pkg/product"
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/log/survey"
+	"github.com/loft-sh/vcluster/pkg/cli/config"
+	"github.com/loft-sh/vcluster/pkg/cli/flags"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"github.com/loft-sh/vcluster/pkg/platform/backup"
+	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+	"github.com/loft-sh/vcluster/pkg/platform/restore"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientpkg "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// RestoreCmd holds the cmd flags
+type RestoreCmd struct {
+	*flags.GlobalFlags
+	Log       log.Logger
+	Namespace string
+	Filename  string
+	Skip      []string
+	Existing  string
+	KMS       string
+	cfg       *config.CLI
+}
+
+// newRestoreCmd creates a new command
+func newRestoreCmd(globalFlags *flags.GlobalFlags, cfg *config.CLI) *cobra.Command {
+	cmd := &RestoreCmd{
+		GlobalFlags: globalFlags,
+		Log:         log.GetInstance(),
+		cfg:         cfg,
+	}
+
+	description := product.ReplaceWithHeader("restore", `
+Restore recreates a vCluster platform management plane from a backup
+
+Example:
+vcluster platform restore --filename backup.yaml
+########################################################
+	`)
+
+	c := &cobra.Command{
+		Use:   "restore",
+		Short: product.Replace("Restore a vCluster platform management plane backup"),
+		Long:  description,
+		Args:  cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			// we need to set the project namespace prefix correctly here
+			_, err := platform.NewClientFromConfig(cobraCmd.Context(), cmd.cfg)
+			if err != nil {
+				return fmt.Errorf("create vCluster platform client: %w", err)
+			}
+
+			return cmd.run(cobraCmd)
+		},
+	}
+
+	c.Flags().StringSliceVar(&cmd.Skip, "skip", []string{}, "What resources the restore should skip. Valid options are: users, teams, accesskeys, sharedsecrets, clusters and clusteraccounttemplates")
+	c.Flags().StringVar(&cmd.Namespace, "namespace", "loft", product.Replace("The namespace vCluster platform was installed into"))
+	c.Flags().StringVar(&cmd.Filename, "filename", "backup.yaml", "The backup file to restore from")
+	c.Flags().StringVar(&cmd.Existing, "existing", string(restore.ExistingSkip), "How to handle objects that already exist. Valid options are: skip, overwrite and fail")
+	c.Flags().StringVar(&cmd.KMS, "kms", "", "Decrypt the backup with this provider before restoring it. Must match the --kms used to create the backup")
+	return c
+}
+
+// run executes the functionality
+func (cmd *RestoreCmd) run(cobraCmd *cobra.Command) error {
+	switch restore.ExistingMode(cmd.Existing) {
+	case restore.ExistingSkip, restore.ExistingOverwrite, restore.ExistingFail:
+	default:
+		return fmt.Errorf("invalid --existing value %q, must be one of skip, overwrite or fail", cmd.Existing)
+	}
+
+	backupBytes, err := os.ReadFile(cmd.Filename)
+	if err != nil {
+		return fmt.Errorf("read backup file: %w", err)
+	}
+
+	if backup.IsEncrypted(backupBytes) {
+		if cmd.KMS == "" {
+			return fmt.Errorf("backup is encrypted, pass --kms with the provider it was encrypted with")
+		}
+		encryptor, err := backup.NewEncryptor(cmd.KMS)
+		if err != nil {
+			return err
+		}
+		backupBytes, err = encryptor.Decrypt(cobraCmd.Context(), backupBytes)
+		if err != nil {
+			return fmt.Errorf("decrypt backup: %w", err)
+		}
+	}
+
+	objects, err := backup.FromYAML(backupBytes)
+	if err != nil {
+		return fmt.Errorf("parse backup file: %w", err)
+	}
+
+	// first load the kube config
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+
+	// load the raw config
+	kubeConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	isInstalled, err := clihelper.IsLoftAlreadyInstalled(cobraCmd.Context(), kubeClient, cmd.Namespace)
+	if err != nil {
+		return err
+	} else if !isInstalled {
+		answer, err := cmd.Log.Question(&survey.QuestionOptions{
+			Question:     fmt.Sprintf(product.Replace("Seems like vCluster platform was not installed into namespace %q, do you want to continue?"), cmd.Namespace),
+			DefaultValue: "Yes",
+			Options:      []string{"Yes", "No"},
+		})
+		if err != nil || answer != "Yes" {
+			return err
+		}
+	}
+
+	ctx := cobraCmd.Context()
+	client, err := clientpkg.New(kubeConfig, clientpkg.Options{Scheme: scheme})
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Infof("Restoring %d objects from %s...", len(objects), cmd.Filename)
+	result, err := restore.All(ctx, client, objects, restore.Options{
+		Skip:     cmd.Skip,
+		Existing: restore.ExistingMode(cmd.Existing),
+	}, func(msg string) {
+		cmd.Log.Info(msg)
+	})
+	if err != nil {
+		return err
+	}
+
+	cmd.Log.Donef("Restore finished: %d created, %d skipped, %d failed", result.Created, result.Skipped, result.Failed)
+	if result.HasFailures() && restore.ExistingMode(cmd.Existing) == restore.ExistingFail {
+		return fmt.Errorf("restore had %d failures", result.Failed)
+	}
+
+	return nil
+}
@@ -2,9 +2,8 @@ package cli
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"strings"
+	"os"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,10 +16,31 @@ import (
 	"github.com/loft-sh/vcluster/pkg/lifecycle"
 	"github.com/loft-sh/vcluster/pkg/platform"
 	"github.com/loft-sh/vcluster/pkg/platform/clihelper"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/term"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 )
 
+// OnPausedMode controls what AddVClusterHelm does with a paused vCluster
+// instead of interactively asking the user, which is required for
+// unattended --all runs.
+type OnPausedMode string
+
+const (
+	// OnPausedSkip leaves the vCluster sleeping; it is added automatically
+	// the next time it wakes.
+	OnPausedSkip OnPausedMode = "skip"
+	// OnPausedWake wakes the vCluster immediately so it can be added now.
+	OnPausedWake OnPausedMode = "wake"
+	// OnPausedFail treats a paused vCluster as an error for this run.
+	OnPausedFail OnPausedMode = "fail"
+)
+
+// DefaultConcurrency bounds how many vClusters AddVClusterHelm adds at once
+// when --all is set, if the caller doesn't configure Concurrency.
+const DefaultConcurrency = 4
+
 type AddVClusterOptions struct {
 	Project                  string
 	ImportName               string
@@ -30,15 +50,52 @@ type AddVClusterOptions struct {
 	Host                     string
 	CertificateAuthorityData []byte
 	All                      bool
+	// Concurrency bounds how many vClusters are added in parallel when All
+	// is set. Defaults to DefaultConcurrency if <= 0.
+	Concurrency int
+	// OnPaused controls what happens to a paused vCluster instead of
+	// asking interactively. Defaults to asking when connected to a
+	// terminal and All is not set, and to OnPausedSkip otherwise.
+	OnPaused OnPausedMode
+	// PrintRegistrationCommand, instead of applying the platform secret
+	// locally, prints a copy-pasteable `vcluster platform register
+	// --from-command <b64> <name> --namespace <namespace>` line that a
+	// tenant can run themselves against the vCluster's cluster, without
+	// needing platform credentials.
+	PrintRegistrationCommand bool
 }
 
+// AddResult is the structured outcome of adding a single vCluster to the
+// platform, so that --all runs can be consumed by CI pipelines instead of
+// scraped from log lines.
+type AddResult struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Snoozed   bool   `json:"snoozed"`
+	// Err is the underlying error, for Go callers such as
+	// AddVClusterErrors.Unwrap. It is never serialized -- encoding/json
+	// marshals a non-nil error to "{}" -- so ErrMsg carries it for
+	// --output=json consumers instead.
+	Err        error  `json:"-"`
+	ErrMsg     string `json:"error,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+}
+
+const (
+	AddStatusAdded               = "added"
+	AddStatusSnoozed             = "snoozed"
+	AddStatusFailed              = "failed"
+	AddStatusRegistrationPrinted = "registration-command-printed"
+)
+
 func AddVClusterHelm(
 	ctx context.Context,
 	options *AddVClusterOptions,
 	globalFlags *flags.GlobalFlags,
 	vClusterName string,
 	log log.Logger,
-) error {
+) ([]AddResult, error) {
 	var vClusters []find.VCluster
 	if options.All {
 		log.Debugf("add vcluster called with --all flag")
@@ -48,22 +105,22 @@ func AddVClusterHelm(
 			})
 		hostClusterRestConfig, err := kubeClientConfig.ClientConfig()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		hostKubeClient, err := kubernetes.NewForConfig(hostClusterRestConfig)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		namespaces, err := hostKubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 		if err != nil {
-			return err
+			return nil, err
 		}
 		log.Debugf("looking for vclusters in %d namespaces", len(namespaces.Items))
 		for _, ns := range namespaces.Items {
 			log.Infof("looking for a vcluster in %s namespace", ns.GetName())
 			vClustersInNamespace, err := find.ListVClusters(ctx, globalFlags.Context, "", ns.GetName(), log)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			if len(vClustersInNamespace) == 0 {
 				log.Infof("no vClusters found in context %s and namespace %s", globalFlags.Context, ns.GetName())
@@ -75,58 +132,94 @@ func AddVClusterHelm(
 		// check if vCluster exists
 		vCluster, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		vClusters = append(vClusters, *vCluster)
 	}
 
 	if len(vClusters) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	restConfig, err := vClusters[0].ClientFactory.ClientConfig()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// create kube client
 	kubeClient, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	addErr := &VClusterAddError{}
-	log.Debugf("trying to add %d vClusters to platform", len(vClusters))
-	for _, vCluster := range vClusters {
-		vCluster := vCluster
-		log.Infof("adding %s vCluster to platform", vCluster.Name)
-		addErr.addErr(vCluster.Name, addVClusterHelm(ctx, options, globalFlags, vCluster.Name, &vCluster, kubeClient, log))
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	log.Debugf("trying to add %d vClusters to platform with concurrency %d", len(vClusters), concurrency)
+
+	results := make([]AddResult, len(vClusters))
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(concurrency)
+	for i, vCluster := range vClusters {
+		i, vCluster := i, vCluster
+		group.Go(func() error {
+			log.Infof("adding %s vCluster to platform", vCluster.Name)
+			results[i] = addVClusterHelm(groupCtx, options, globalFlags, vCluster.Name, &vCluster, kubeClient, log)
+			return nil
+		})
 	}
+	_ = group.Wait()
 
-	return addErr.CombinedError()
+	return results, (&AddVClusterErrors{Results: results}).asError()
 }
 
-type VClusterAddError struct {
-	errs []error
+// AddVClusterErrors collects the failures from an AddVClusterHelm run. It
+// implements errors.Is/errors.Unwrap over its members instead of joining
+// them into a single `|`-delimited string, so callers can match on the
+// underlying error kind instead of parsing text.
+type AddVClusterErrors struct {
+	Results []AddResult
 }
 
-func (vce *VClusterAddError) CombinedError() error {
-	if len(vce.errs) == 0 {
+func (e *AddVClusterErrors) asError() error {
+	if e.errorCount() == 0 {
 		return nil
-	} else if len(vce.errs) == 1 {
-		return vce.errs[0]
 	}
-	errMsg := strings.Builder{}
-	for _, err := range vce.errs {
-		_, _ = errMsg.WriteString(err.Error() + "|")
+	return e
+}
+
+func (e *AddVClusterErrors) errorCount() int {
+	count := 0
+	for _, result := range e.Results {
+		if result.Err != nil {
+			count++
+		}
 	}
-	return errors.New(errMsg.String())
+	return count
 }
 
-func (vce *VClusterAddError) addErr(vClusterName string, err error) {
-	if err == nil {
-		return
+func (e *AddVClusterErrors) Error() string {
+	msg := fmt.Sprintf("%d vCluster(s) failed to be added to the platform:", e.errorCount())
+	for _, result := range e.Results {
+		if result.Err != nil {
+			msg += fmt.Sprintf("\n  - %s/%s: %v", result.Namespace, result.Name, result.Err)
+		}
 	}
-	vce.errs = append(vce.errs, fmt.Errorf("cannot add vcluster %s: %w", vClusterName, err))
+	return msg
+}
+
+// Unwrap lets errors.Is/errors.As reach into the per-vCluster errors this
+// wraps, e.g. errors.Is(err, context.DeadlineExceeded).
+func (e *AddVClusterErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Results))
+	for _, result := range e.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("cannot add vcluster %s/%s: %w", result.Namespace, result.Name, result.Err))
+		}
+	}
+	return errs
 }
 
 func addVClusterHelm(
@@ -137,76 +230,171 @@ func addVClusterHelm(
 	vCluster *find.VCluster,
 	kubeClient *kubernetes.Clientset,
 	log log.Logger,
-) error {
-	snoozed := false
-	// If the vCluster was paused with the helm driver, adding it to the platform will only create the secret for registration
-	// which leads to confusing behavior for the user since they won't see the cluster in the platform UI until it is resumed.
-	if lifecycle.IsPaused(vCluster) {
-		log.Infof("vCluster %s is currently sleeping. It will not be added to the platform until it wakes again.", vCluster.Name)
-
-		snoozeConfirmation := "No. Leave it sleeping. (It will be added automatically on next wakeup)"
-		answer, err := log.Question(&survey.QuestionOptions{
-			Question:     fmt.Sprintf("Would you like to wake vCluster %s now to add immediately?", vCluster.Name),
-			DefaultValue: snoozeConfirmation,
-			Options: []string{
-				snoozeConfirmation,
-				"Yes. Wake and add now.",
-			},
+) AddResult {
+	start := time.Now()
+	result := AddResult{Namespace: vCluster.Namespace, Name: vCluster.Name}
+
+	snoozed, err := handlePaused(ctx, options, globalFlags, vClusterName, vCluster, log)
+	if err != nil {
+		result.Err = err
+		result.ErrMsg = err.Error()
+		result.Status = AddStatusFailed
+		result.DurationMS = time.Since(start).Milliseconds()
+		return result
+	}
+	result.Snoozed = snoozed
+
+	if !snoozed && options.PrintRegistrationCommand {
+		importName := options.ImportName
+		if importName == "" {
+			importName = vCluster.Name
+		}
+
+		command, err := PrintableRegistrationCommand(RegistrationBundle{
+			AccessKey:                options.AccessKey,
+			Host:                     options.Host,
+			CertificateAuthorityData: options.CertificateAuthorityData,
+			ImportName:               importName,
+			Project:                  options.Project,
+			Insecure:                 options.Insecure,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to capture your response %w", err)
+			result.Err = fmt.Errorf("build registration command: %w", err)
+			result.ErrMsg = result.Err.Error()
+			result.Status = AddStatusFailed
+			result.DurationMS = time.Since(start).Milliseconds()
+			return result
 		}
 
-		if snoozed = answer == snoozeConfirmation; !snoozed {
-			if err = ResumeHelm(ctx, globalFlags, vClusterName, log); err != nil {
-				return fmt.Errorf("failed to wake up vCluster %s: %w", vClusterName, err)
-			}
+		log.Donef("Run the following inside vCluster %s/%s's cluster to register it:", vCluster.Namespace, vCluster.Name)
+		log.Infof("%s %s --namespace %s", command, vCluster.Name, vCluster.Namespace)
 
-			err = wait.PollUntilContextTimeout(ctx, time.Second, clihelper.Timeout(), false, func(ctx context.Context) (done bool, err error) {
-				vCluster, err = find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
-				if err != nil {
-					return false, err
-				}
+		result.Status = AddStatusRegistrationPrinted
+		result.DurationMS = time.Since(start).Milliseconds()
+		return result
+	}
 
-				return !lifecycle.IsPaused(vCluster), nil
-			})
+	if !snoozed {
+		// apply platform secret
+		err := platform.ApplyPlatformSecret(
+			ctx,
+			globalFlags.LoadedConfig(log),
+			kubeClient,
+			options.ImportName,
+			vCluster.Namespace,
+			options.Project,
+			options.AccessKey,
+			options.Host,
+			options.Insecure,
+			options.CertificateAuthorityData,
+		)
+		if err != nil {
+			result.Err = err
+			result.ErrMsg = err.Error()
+			result.Status = AddStatusFailed
+			result.DurationMS = time.Since(start).Milliseconds()
+			return result
+		}
 
+		// restart vCluster
+		if options.Restart {
+			err = lifecycle.DeletePods(ctx, kubeClient, "app=vcluster,release="+vCluster.Name, vCluster.Namespace, log)
 			if err != nil {
-				return fmt.Errorf("error waiting for vCluster to wake up %w", err)
+				result.Err = fmt.Errorf("delete vcluster workloads: %w", err)
+				result.ErrMsg = result.Err.Error()
+				result.Status = AddStatusFailed
+				result.DurationMS = time.Since(start).Milliseconds()
+				return result
 			}
 		}
 	}
 
-	// apply platform secret
-	err := platform.ApplyPlatformSecret(
-		ctx,
-		globalFlags.LoadedConfig(log),
-		kubeClient,
-		options.ImportName,
-		vCluster.Namespace,
-		options.Project,
-		options.AccessKey,
-		options.Host,
-		options.Insecure,
-		options.CertificateAuthorityData,
-	)
-	if err != nil {
-		return err
-	}
-
-	// restart vCluster
-	if options.Restart {
-		err = lifecycle.DeletePods(ctx, kubeClient, "app=vcluster,release="+vCluster.Name, vCluster.Namespace, log)
-		if err != nil {
-			return fmt.Errorf("delete vcluster workloads: %w", err)
-		}
-	}
-
+	result.DurationMS = time.Since(start).Milliseconds()
 	if snoozed {
+		result.Status = AddStatusSnoozed
 		log.Infof("vCluster %s/%s will be added the next time it awakes", vCluster.Namespace, vCluster.Name)
 		log.Donef("Run 'vcluster wakeup --help' to learn how to wake up vCluster %s/%s to complete the add operation.", vCluster.Namespace, vCluster.Name)
 	} else {
+		result.Status = AddStatusAdded
 		log.Donef("Successfully added vCluster %s/%s", vCluster.Namespace, vCluster.Name)
 	}
-	return nil
+
+	return result
+}
+
+// handlePaused decides whether a paused vCluster should be left sleeping
+// or woken up before being added, either by asking the user (when attached
+// to a terminal, without --all and no --on-paused was given) or by applying options.OnPaused
+// directly, so that --all runs can execute unattended.
+func handlePaused(
+	ctx context.Context,
+	options *AddVClusterOptions,
+	globalFlags *flags.GlobalFlags,
+	vClusterName string,
+	vCluster *find.VCluster,
+	log log.Logger,
+) (snoozed bool, err error) {
+	if !lifecycle.IsPaused(vCluster) {
+		return false, nil
+	}
+
+	log.Infof("vCluster %s is currently sleeping. It will not be added to the platform until it wakes again.", vCluster.Name)
+
+	onPaused := options.OnPaused
+	if onPaused == "" {
+		// An --all run can pause multiple vClusters to wake at once, and
+		// the add for each runs in its own goroutine (see AddVClusterHelm);
+		// prompting here would let their survey.QuestionOptions calls
+		// interleave on the same terminal. Only ask interactively for a
+		// single named vCluster, where there is exactly one prompt at a time.
+		if !isInteractive() || options.All {
+			onPaused = OnPausedSkip
+		} else {
+			snoozeConfirmation := "No. Leave it sleeping. (It will be added automatically on next wakeup)"
+			answer, err := log.Question(&survey.QuestionOptions{
+				Question:     fmt.Sprintf("Would you like to wake vCluster %s now to add immediately?", vCluster.Name),
+				DefaultValue: snoozeConfirmation,
+				Options: []string{
+					snoozeConfirmation,
+					"Yes. Wake and add now.",
+				},
+			})
+			if err != nil {
+				return false, fmt.Errorf("failed to capture your response %w", err)
+			}
+			if answer == snoozeConfirmation {
+				onPaused = OnPausedSkip
+			} else {
+				onPaused = OnPausedWake
+			}
+		}
+	}
+
+	switch onPaused {
+	case OnPausedFail:
+		return false, fmt.Errorf("vCluster %s is paused; pass --on-paused=wake or --on-paused=skip, or resume it manually", vCluster.Name)
+	case OnPausedWake:
+		if err := ResumeHelm(ctx, globalFlags, vClusterName, log); err != nil {
+			return false, fmt.Errorf("failed to wake up vCluster %s: %w", vClusterName, err)
+		}
+
+		err := wait.PollUntilContextTimeout(ctx, time.Second, clihelper.Timeout(), false, func(ctx context.Context) (bool, error) {
+			current, err := find.GetVCluster(ctx, globalFlags.Context, vClusterName, globalFlags.Namespace, log)
+			if err != nil {
+				return false, err
+			}
+			*vCluster = *current
+			return !lifecycle.IsPaused(vCluster), nil
+		})
+		if err != nil {
+			return false, fmt.Errorf("error waiting for vCluster to wake up %w", err)
+		}
+		return false, nil
+	default: // OnPausedSkip
+		return true, nil
+	}
+}
+
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
 }
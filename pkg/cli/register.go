@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/loft-sh/log"
+	"github.com/loft-sh/vcluster/pkg/lifecycle"
+	"github.com/loft-sh/vcluster/pkg/platform"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// RegistrationBundle is everything a tenant cluster needs to add itself to
+// the platform without the platform CLI or credentials: just the bits
+// addVClusterHelm would otherwise apply as a Secret via ApplyPlatformSecret.
+type RegistrationBundle struct {
+	AccessKey                string `json:"accessKey"`
+	Host                     string `json:"host"`
+	CertificateAuthorityData []byte `json:"certificateAuthorityData,omitempty"`
+	ImportName               string `json:"importName"`
+	Project                  string `json:"project"`
+	Insecure                 bool   `json:"insecure"`
+}
+
+// EncodeRegistrationCommand renders bundle as the base64 blob that goes
+// into a `vcluster platform register --from-command` invocation.
+func EncodeRegistrationCommand(bundle RegistrationBundle) (string, error) {
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return "", fmt.Errorf("marshal registration bundle: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeRegistrationCommand reverses EncodeRegistrationCommand.
+func DecodeRegistrationCommand(encoded string) (*RegistrationBundle, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode registration command: %w", err)
+	}
+
+	bundle := &RegistrationBundle{}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("unmarshal registration bundle: %w", err)
+	}
+
+	return bundle, nil
+}
+
+// PrintableRegistrationCommand is the `vcluster platform register
+// --from-command <b64>` part of the line a platform admin hands to a tenant,
+// who runs it with access to the target cluster's current kube context but
+// without ever needing platform credentials. It does not include the
+// vCluster name argument that register requires; callers append it.
+func PrintableRegistrationCommand(bundle RegistrationBundle) (string, error) {
+	encoded, err := EncodeRegistrationCommand(bundle)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("vcluster platform register --from-command %s", encoded), nil
+}
+
+// RegisterVCluster decodes a registration command produced by
+// PrintableRegistrationCommand and applies it against the vCluster running
+// in namespace of the cluster the current kube context points at -- the
+// same secret-apply (and optional restart) addVClusterHelm performs, minus
+// ever needing to authenticate against the platform itself.
+func RegisterVCluster(ctx context.Context, encoded, namespace, vClusterName string, restart bool, log log.Logger) error {
+	bundle, err := DecodeRegistrationCommand(encoded)
+	if err != nil {
+		return err
+	}
+
+	kubeClientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{})
+	restConfig, err := kubeClientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("there is an error loading your current kube config (%w), please make sure you have access to a kubernetes cluster and the command `kubectl get namespaces` is working", err)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Registering vCluster %s/%s with project %s", namespace, vClusterName, bundle.Project)
+	err = platform.ApplyPlatformSecret(
+		ctx,
+		nil,
+		kubeClient,
+		bundle.ImportName,
+		namespace,
+		bundle.Project,
+		bundle.AccessKey,
+		bundle.Host,
+		bundle.Insecure,
+		bundle.CertificateAuthorityData,
+	)
+	if err != nil {
+		return err
+	}
+
+	if restart {
+		if err := lifecycle.DeletePods(ctx, kubeClient, "app=vcluster,release="+vClusterName, namespace, log); err != nil {
+			return fmt.Errorf("delete vcluster workloads: %w", err)
+		}
+	}
+
+	log.Donef("Successfully registered vCluster %s/%s", namespace, vClusterName)
+	return nil
+}
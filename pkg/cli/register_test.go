@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegistrationCommandRoundTrip(t *testing.T) {
+	bundle := RegistrationBundle{
+		AccessKey:                "some-access-key",
+		Host:                     "https://platform.example.com",
+		CertificateAuthorityData: []byte("-----BEGIN CERTIFICATE-----\n...\n-----END CERTIFICATE-----"),
+		ImportName:               "my-vcluster",
+		Project:                  "my-project",
+		Insecure:                 true,
+	}
+
+	encoded, err := EncodeRegistrationCommand(bundle)
+	if err != nil {
+		t.Fatalf("EncodeRegistrationCommand: %v", err)
+	}
+
+	decoded, err := DecodeRegistrationCommand(encoded)
+	if err != nil {
+		t.Fatalf("DecodeRegistrationCommand: %v", err)
+	}
+
+	if !reflect.DeepEqual(*decoded, bundle) {
+		t.Fatalf("round trip mismatch: got %+v, want %+v", *decoded, bundle)
+	}
+}
+
+func TestDecodeRegistrationCommandRejectsInvalidBase64(t *testing.T) {
+	if _, err := DecodeRegistrationCommand("not-valid-base64!!!"); err == nil {
+		t.Fatal("expected DecodeRegistrationCommand to reject invalid base64")
+	}
+}
+
+func TestPrintableRegistrationCommandEmbedsEncodedBundle(t *testing.T) {
+	bundle := RegistrationBundle{AccessKey: "key", Host: "host", ImportName: "name", Project: "project"}
+
+	command, err := PrintableRegistrationCommand(bundle)
+	if err != nil {
+		t.Fatalf("PrintableRegistrationCommand: %v", err)
+	}
+
+	encoded, err := EncodeRegistrationCommand(bundle)
+	if err != nil {
+		t.Fatalf("EncodeRegistrationCommand: %v", err)
+	}
+
+	want := "vcluster platform register --from-command " + encoded
+	if command != want {
+		t.Fatalf("PrintableRegistrationCommand() = %q, want %q", command, want)
+	}
+}
@@ -0,0 +1,165 @@
+// Package backup implements the object collection and (de)serialization
+// logic shared by the `vcluster platform backup` and `vcluster platform
+// restore` commands.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// Resources are the resource kinds that a backup can contain, in the order
+// they are written (and, importantly, the order they must be restored in so
+// that owner references resolve correctly).
+var Resources = []string{
+	"users",
+	"teams",
+	"accesskeys",
+	"sharedsecrets",
+	"clusters",
+	"clusteraccounttemplates",
+}
+
+// listOf returns an empty list object and the Kind of the items it contains
+// for the given resource name.
+func listOf(resource string) (client.ObjectList, string, error) {
+	switch resource {
+	case "users":
+		return &storagev1.UserList{}, "User", nil
+	case "teams":
+		return &storagev1.TeamList{}, "Team", nil
+	case "accesskeys":
+		return &storagev1.AccessKeyList{}, "AccessKey", nil
+	case "sharedsecrets":
+		return &storagev1.SharedSecretList{}, "SharedSecret", nil
+	case "clusters":
+		return &storagev1.ClusterList{}, "Cluster", nil
+	case "clusteraccounttemplates":
+		return &storagev1.ClusterAccountTemplateList{}, "ClusterAccountTemplate", nil
+	default:
+		return nil, "", fmt.Errorf("unknown backup resource %q", resource)
+	}
+}
+
+// itemsOf extracts the individual objects out of a typed list, stamping
+// TypeMeta onto each one so that it survives a round trip through YAML.
+func itemsOf(resource, kind string, list client.ObjectList) ([]client.Object, error) {
+	typeMeta := metav1.TypeMeta{Kind: kind, APIVersion: storagev1.SchemeGroupVersion.String()}
+
+	switch l := list.(type) {
+	case *storagev1.UserList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	case *storagev1.TeamList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	case *storagev1.AccessKeyList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	case *storagev1.SharedSecretList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	case *storagev1.ClusterList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	case *storagev1.ClusterAccountTemplateList:
+		items := make([]client.Object, 0, len(l.Items))
+		for i := range l.Items {
+			l.Items[i].TypeMeta = typeMeta
+			items = append(items, &l.Items[i])
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unexpected list type for resource %q", resource)
+	}
+}
+
+// All collects every object that should be part of a backup, skipping any
+// resource named in skip. It returns the objects it managed to collect as
+// well as the errors it ran into along the way so that a partial backup can
+// still be written.
+func All(ctx context.Context, c client.Client, skip []string, log func(string)) ([]client.Object, []error) {
+	skipSet := make(map[string]bool, len(skip))
+	for _, s := range skip {
+		skipSet[s] = true
+	}
+
+	var (
+		objects []client.Object
+		errs    []error
+	)
+	for _, resource := range Resources {
+		if skipSet[resource] {
+			log(fmt.Sprintf("Skipping %s", resource))
+			continue
+		}
+
+		list, kind, err := listOf(resource)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := c.List(ctx, list); err != nil {
+			errs = append(errs, fmt.Errorf("list %s: %w", resource, err))
+			continue
+		}
+
+		items, err := itemsOf(resource, kind, list)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		log(fmt.Sprintf("Backing up %d %s", len(items), resource))
+		objects = append(objects, items...)
+	}
+
+	return objects, errs
+}
+
+// ToYAML renders a set of objects as a single multi-document YAML stream,
+// one document per object, in the order they were given.
+func ToYAML(objects []client.Object) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for i, obj := range objects {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+
+		raw, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("marshal object: %w", err)
+		}
+
+		buf.Write(raw)
+	}
+
+	return buf.Bytes(), nil
+}
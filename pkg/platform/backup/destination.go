@@ -0,0 +1,78 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// key is the object key a backup is written under once it has a
+// destination more structured than a flat local file, mirroring the layout
+// Velero uses for its BackupStorageLocations.
+func key(now time.Time) string {
+	return fmt.Sprintf("loft-backup/%s.yaml", now.UTC().Format("20060102T150405Z"))
+}
+
+// Destination is a sink a backup can be written to. Implementations exist
+// for local files as well as the major object storage providers.
+type Destination interface {
+	// Write stores data under the destination's backup key and returns the
+	// fully qualified location it was written to (e.g. a file path or an
+	// object URL), for logging purposes.
+	Write(ctx context.Context, data []byte) (string, error)
+}
+
+// ObjectInfo describes one backup previously written to a PruningDestination.
+type ObjectInfo struct {
+	Key       string
+	Timestamp time.Time
+}
+
+// PruningDestination is a Destination that can also enumerate and remove
+// the backups it holds, which a backup schedule needs in order to enforce
+// --keep-last/--keep-days retention.
+type PruningDestination interface {
+	Destination
+	List(ctx context.Context) ([]ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewDestination resolves a --destination URI (file://, s3://, gs:// or
+// azure://) into a Destination. filename is used as a fallback when uri is
+// empty, so that --filename keeps working for users who haven't switched
+// over to --destination yet.
+func NewDestination(uri, filename string) (Destination, error) {
+	if uri == "" {
+		return newFileDestination("file://" + filename)
+	}
+
+	scheme := strings.SplitN(uri, "://", 2)[0]
+	switch scheme {
+	case "file":
+		return newFileDestination(uri)
+	case "s3":
+		return newS3Destination(uri)
+	case "gs":
+		return newGCSDestination(uri)
+	case "azure":
+		return newAzureDestination(uri)
+	default:
+		return nil, fmt.Errorf("unsupported destination scheme %q, must be one of file, s3, gs or azure", scheme)
+	}
+}
+
+func parseBucketAndPrefix(uri string) (bucket, prefix string, err error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return "", "", fmt.Errorf("parse destination %q: %w", uri, err)
+	}
+
+	bucket = parsed.Host
+	if bucket == "" {
+		return "", "", fmt.Errorf("destination %q is missing a bucket name", uri)
+	}
+
+	return bucket, strings.Trim(parsed.Path, "/"), nil
+}
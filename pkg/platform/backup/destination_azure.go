@@ -0,0 +1,134 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+)
+
+// azureDestination writes the backup object to an Azure Blob Storage
+// container. Credentials are resolved from the standard AZURE_STORAGE_*
+// environment variables, falling back to azidentity's default chain
+// (managed identity, az cli, …) when no account key is configured.
+type azureDestination struct {
+	client          *azblob.Client
+	container       string
+	prefix          string
+	encryptionScope string
+}
+
+func newAzureDestination(uri string) (*azureDestination, error) {
+	// azure://<account>.blob.core.windows.net/<container>/<prefix>
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse destination %q: %w", uri, err)
+	}
+
+	account := parsed.Host
+	if account == "" {
+		return nil, fmt.Errorf("destination %q is missing a storage account host", uri)
+	}
+
+	parts := strings.SplitN(strings.Trim(parsed.Path, "/"), "/", 2)
+	if parts[0] == "" {
+		return nil, fmt.Errorf("destination %q is missing a container name", uri)
+	}
+	container := parts[0]
+	prefix := ""
+	if len(parts) == 2 {
+		prefix = parts[1]
+	}
+
+	serviceURL := fmt.Sprintf("https://%s", account)
+
+	var client *azblob.Client
+	if accountName, accountKey := os.Getenv("AZURE_STORAGE_ACCOUNT"), os.Getenv("AZURE_STORAGE_KEY"); accountName != "" && accountKey != "" {
+		cred, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+		if err != nil {
+			return nil, fmt.Errorf("create Azure shared key credential: %w", err)
+		}
+		client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create Azure blob client: %w", err)
+		}
+	} else {
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("create Azure default credential: %w", err)
+		}
+		client, err = azblob.NewClient(serviceURL, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create Azure blob client: %w", err)
+		}
+	}
+
+	// encryption-scope enables server-side encryption with a customer-
+	// managed key instead of the Microsoft-managed key Azure applies by
+	// default to every blob.
+	return &azureDestination{
+		client:          client,
+		container:       container,
+		prefix:          prefix,
+		encryptionScope: parsed.Query().Get("encryption-scope"),
+	}, nil
+}
+
+func (d *azureDestination) Write(ctx context.Context, data []byte) (string, error) {
+	objectKey := key(time.Now())
+	if d.prefix != "" {
+		objectKey = d.prefix + "/" + objectKey
+	}
+
+	opts := &azblob.UploadBufferOptions{
+		HTTPHeaders: &blob.HTTPHeaders{
+			BlobContentType: to.Ptr("application/yaml"),
+		},
+	}
+	if d.encryptionScope != "" {
+		opts.CPKScopeInfo = &blob.CPKScopeInfo{EncryptionScope: to.Ptr(d.encryptionScope)}
+	}
+
+	if _, err := d.client.UploadBuffer(ctx, d.container, objectKey, data, opts); err != nil {
+		return "", fmt.Errorf("upload blob %s/%s: %w", d.container, objectKey, err)
+	}
+
+	return fmt.Sprintf("azure://%s/%s", d.container, objectKey), nil
+}
+
+func (d *azureDestination) backupPrefix() string {
+	if d.prefix != "" {
+		return d.prefix + "/loft-backup/"
+	}
+	return "loft-backup/"
+}
+
+func (d *azureDestination) List(ctx context.Context) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	prefix := d.backupPrefix()
+	pager := d.client.NewListBlobsFlatPager(d.container, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list blobs in %s: %w", d.container, err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			objects = append(objects, ObjectInfo{Key: *item.Name, Timestamp: *item.Properties.LastModified})
+		}
+	}
+	return objects, nil
+}
+
+func (d *azureDestination) Delete(ctx context.Context, key string) error {
+	if _, err := d.client.DeleteBlob(ctx, d.container, key, nil); err != nil {
+		return fmt.Errorf("delete blob %s/%s: %w", d.container, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileDestination writes the backup to a local path, preserving the
+// pre-existing `os.WriteFile(cmd.Filename, …)` behavior for users who don't
+// configure an object storage destination. When path is a directory (it
+// ends in "/"), each backup is written under it using the same
+// loft-backup/<timestamp>.yaml layout the remote backends use, which is
+// what makes retention (List/Delete) meaningful for this backend too.
+type fileDestination struct {
+	path string
+}
+
+func newFileDestination(uri string) (*fileDestination, error) {
+	return &fileDestination{path: strings.TrimPrefix(uri, "file://")}, nil
+}
+
+func (d *fileDestination) Write(_ context.Context, data []byte) (string, error) {
+	path := d.path
+	if strings.HasSuffix(path, "/") {
+		path = filepath.Join(path, key(time.Now()))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return "", fmt.Errorf("create backup directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("write backup to %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+func (d *fileDestination) List(_ context.Context) ([]ObjectInfo, error) {
+	if !strings.HasSuffix(d.path, "/") {
+		return nil, fmt.Errorf("file destination %q is a single file, not a directory; retention requires a trailing slash", d.path)
+	}
+
+	var objects []ObjectInfo
+	root := filepath.Join(d.path, "loft-backup")
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("list backups in %s: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, ObjectInfo{
+			Key:       filepath.Join("loft-backup", entry.Name()),
+			Timestamp: info.ModTime(),
+		})
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Timestamp.Before(objects[j].Timestamp) })
+	return objects, nil
+}
+
+func (d *fileDestination) Delete(_ context.Context, key string) error {
+	if err := os.Remove(filepath.Join(d.path, key)); err != nil {
+		return fmt.Errorf("delete backup %s: %w", key, err)
+	}
+	return nil
+}
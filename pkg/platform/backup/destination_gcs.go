@@ -0,0 +1,108 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// gcsDestination writes the backup object to a Google Cloud Storage
+// bucket. Credentials are resolved the standard way, via
+// GOOGLE_APPLICATION_CREDENTIALS or the ambient GCE/GKE service account.
+type gcsDestination struct {
+	bucket string
+	prefix string
+	kmsKey string
+}
+
+func newGCSDestination(uri string) (*gcsDestination, error) {
+	bucket, prefix, err := parseBucketAndPrefix(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse destination %q: %w", uri, err)
+	}
+
+	return &gcsDestination{
+		bucket: bucket,
+		prefix: prefix,
+		kmsKey: parsed.Query().Get("kms-key"),
+	}, nil
+}
+
+func (d *gcsDestination) Write(ctx context.Context, data []byte) (string, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("create GCS client: %w", err)
+	}
+	defer client.Close()
+
+	objectKey := key(time.Now())
+	if d.prefix != "" {
+		objectKey = d.prefix + "/" + objectKey
+	}
+
+	writer := client.Bucket(d.bucket).Object(objectKey).NewWriter(ctx)
+	if d.kmsKey != "" {
+		writer.KMSKeyName = d.kmsKey
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		_ = writer.Close()
+		return "", fmt.Errorf("write object gs://%s/%s: %w", d.bucket, objectKey, err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("finalize object gs://%s/%s: %w", d.bucket, objectKey, err)
+	}
+
+	return fmt.Sprintf("gs://%s/%s", d.bucket, objectKey), nil
+}
+
+func (d *gcsDestination) backupPrefix() string {
+	if d.prefix != "" {
+		return d.prefix + "/loft-backup/"
+	}
+	return "loft-backup/"
+}
+
+func (d *gcsDestination) List(ctx context.Context) ([]ObjectInfo, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create GCS client: %w", err)
+	}
+	defer client.Close()
+
+	var objects []ObjectInfo
+	it := client.Bucket(d.bucket).Objects(ctx, &storage.Query{Prefix: d.backupPrefix()})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("list objects in %s: %w", d.bucket, err)
+		}
+		objects = append(objects, ObjectInfo{Key: attrs.Name, Timestamp: attrs.Updated})
+	}
+	return objects, nil
+}
+
+func (d *gcsDestination) Delete(ctx context.Context, key string) error {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("create GCS client: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Bucket(d.bucket).Object(key).Delete(ctx); err != nil {
+		return fmt.Errorf("delete object %s/%s: %w", d.bucket, key, err)
+	}
+	return nil
+}
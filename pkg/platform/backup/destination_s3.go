@@ -0,0 +1,125 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3Destination writes the backup object to an S3 (or S3-compatible)
+// bucket. Credentials are resolved through the standard AWS SDK chain
+// (AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, shared config/credentials
+// files, EC2/EKS instance roles, …), the same as every other AWS-talking
+// command in this repo.
+type s3Destination struct {
+	client    *s3.Client
+	bucket    string
+	prefix    string
+	sse       s3types.ServerSideEncryption
+	kmsKeyARN string
+}
+
+func newS3Destination(uri string) (*s3Destination, error) {
+	bucket, prefix, err := parseBucketAndPrefix(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse destination %q: %w", uri, err)
+	}
+	query := parsed.Query()
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+	if region := query.Get("region"); region != "" {
+		cfg.Region = region
+	}
+
+	dest := &s3Destination{
+		client: s3.NewFromConfig(cfg),
+		bucket: bucket,
+		prefix: prefix,
+	}
+
+	switch query.Get("sse") {
+	case "", "none":
+	case "aws:kms":
+		dest.sse = s3types.ServerSideEncryptionAwsKms
+		dest.kmsKeyARN = query.Get("kms-key-id")
+	case "AES256":
+		dest.sse = s3types.ServerSideEncryptionAes256
+	default:
+		return nil, fmt.Errorf("unsupported sse mode %q for s3 destination", query.Get("sse"))
+	}
+
+	return dest, nil
+}
+
+func (d *s3Destination) Write(ctx context.Context, data []byte) (string, error) {
+	objectKey := key(time.Now())
+	if d.prefix != "" {
+		objectKey = d.prefix + "/" + objectKey
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(objectKey),
+		Body:   bytes.NewReader(data),
+	}
+	if d.sse != "" {
+		input.ServerSideEncryption = d.sse
+		if d.kmsKeyARN != "" {
+			input.SSEKMSKeyId = aws.String(d.kmsKeyARN)
+		}
+	}
+
+	if _, err := d.client.PutObject(ctx, input); err != nil {
+		return "", fmt.Errorf("put object %s/%s: %w", d.bucket, objectKey, err)
+	}
+
+	return fmt.Sprintf("s3://%s/%s", d.bucket, objectKey), nil
+}
+
+func (d *s3Destination) backupPrefix() string {
+	if d.prefix != "" {
+		return d.prefix + "/loft-backup/"
+	}
+	return "loft-backup/"
+}
+
+func (d *s3Destination) List(ctx context.Context) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(d.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.bucket),
+		Prefix: aws.String(d.backupPrefix()),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list objects in %s: %w", d.bucket, err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{Key: aws.ToString(obj.Key), Timestamp: aws.ToTime(obj.LastModified)})
+		}
+	}
+	return objects, nil
+}
+
+func (d *s3Destination) Delete(ctx context.Context, key string) error {
+	_, err := d.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(d.bucket), Key: aws.String(key)})
+	if err != nil {
+		return fmt.Errorf("delete object %s/%s: %w", d.bucket, key, err)
+	}
+	return nil
+}
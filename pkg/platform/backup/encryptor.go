@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// envelopeMagic identifies an encrypted backup payload so that restore can
+// tell apart plaintext YAML from an encrypted envelope without needing a
+// separate flag.
+var envelopeMagic = [4]byte{'L', 'K', 'B', 'K'}
+
+// envelopeHeader is written ahead of the ciphertext. It carries everything
+// a KMS provider needs to unwrap the data key again, but never the data
+// key itself -- or any other secret -- in the clear.
+type envelopeHeader struct {
+	// Provider identifies which --kms provider produced this envelope, for
+	// diagnostics only: restore always decrypts with the --kms URI it was
+	// given, never with Provider. It is stripped of query parameters (and
+	// any userinfo) before being stored here, since for passphrase:// that
+	// query string is the passphrase itself, and this header is written
+	// in cleartext at the head of the very file it protects.
+	Provider string `json:"provider"`
+	// WrappedDEK is the 256-bit data encryption key, encrypted ("wrapped")
+	// by the KMS provider's key.
+	WrappedDEK []byte `json:"wrappedDek"`
+	// Nonce is the AES-GCM nonce used to seal the payload with the data
+	// encryption key.
+	Nonce []byte `json:"nonce"`
+}
+
+// KeyWrapper wraps and unwraps the random data encryption key generated
+// for each backup. Each supported --kms provider implements this against
+// its own API.
+type KeyWrapper interface {
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// Encryptor envelope-encrypts (and decrypts) a backup payload.
+type Encryptor interface {
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// NewEncryptor resolves a --kms URI (aws-kms://, gcp-kms://, azure-kv:// or
+// passphrase://) into an Encryptor that performs envelope encryption: a
+// random 256-bit data key encrypts the payload with AES-GCM, and the
+// provider's key wraps the data key.
+func NewEncryptor(uri string) (Encryptor, error) {
+	if uri == "" {
+		return nil, nil
+	}
+
+	scheme := strings.SplitN(uri, "://", 2)[0]
+	var wrapper KeyWrapper
+	var err error
+	switch scheme {
+	case "aws-kms":
+		wrapper, err = newAWSKMSWrapper(uri)
+	case "gcp-kms":
+		wrapper, err = newGCPKMSWrapper(uri)
+	case "azure-kv":
+		wrapper, err = newAzureKeyVaultWrapper(uri)
+	case "passphrase":
+		wrapper, err = newPassphraseWrapper(uri)
+	default:
+		return nil, fmt.Errorf("unsupported kms scheme %q, must be one of aws-kms, gcp-kms, azure-kv or passphrase", scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &envelopeEncryptor{provider: uri, wrapper: wrapper}, nil
+}
+
+// IsEncrypted reports whether data is an envelope produced by Encrypt,
+// which restore uses to decide whether it needs a --kms flag at all.
+func IsEncrypted(data []byte) bool {
+	return len(data) >= len(envelopeMagic) && bytes.Equal(data[:len(envelopeMagic)], envelopeMagic[:])
+}
+
+// sanitizeProviderURI strips query parameters and userinfo from a --kms URI
+// before it is stored in the envelope header. For passphrase:// the query
+// string is the passphrase itself, and this header is written in cleartext
+// at the head of the very file it protects, so only the scheme and path
+// survive. If uri does not parse as a URI, it is returned unchanged.
+func sanitizeProviderURI(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	parsed.RawQuery = ""
+	parsed.User = nil
+	if parsed.Host == "" && parsed.Path == "" && parsed.Opaque == "" {
+		// url.URL.String drops the "//" of an empty authority, which would
+		// turn passphrase:// into passphrase:.
+		return parsed.Scheme + "://"
+	}
+	return parsed.String()
+}
+
+type envelopeEncryptor struct {
+	provider string
+	wrapper  KeyWrapper
+}
+
+func (e *envelopeEncryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("generate data encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("init AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init AES-GCM: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	wrappedDEK, err := e.wrapper.WrapKey(ctx, dek)
+	if err != nil {
+		return nil, fmt.Errorf("wrap data encryption key: %w", err)
+	}
+
+	header, err := json.Marshal(envelopeHeader{
+		Provider:   sanitizeProviderURI(e.provider),
+		WrappedDEK: wrappedDEK,
+		Nonce:      nonce,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal envelope header: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	out := &bytes.Buffer{}
+	out.Write(envelopeMagic[:])
+	_ = binary.Write(out, binary.BigEndian, uint32(len(header)))
+	out.Write(header)
+	out.Write(ciphertext)
+	return out.Bytes(), nil
+}
+
+func (e *envelopeEncryptor) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("backup is not an encrypted envelope")
+	}
+	data = data[len(envelopeMagic):]
+
+	if len(data) < 4 {
+		return nil, fmt.Errorf("truncated envelope header")
+	}
+	headerLen := binary.BigEndian.Uint32(data[:4])
+	data = data[4:]
+	if uint32(len(data)) < headerLen {
+		return nil, fmt.Errorf("truncated envelope header")
+	}
+
+	var header envelopeHeader
+	if err := json.Unmarshal(data[:headerLen], &header); err != nil {
+		return nil, fmt.Errorf("unmarshal envelope header: %w", err)
+	}
+	ciphertext := data[headerLen:]
+
+	dek, err := e.wrapper.UnwrapKey(ctx, header.WrappedDEK)
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("init AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init AES-GCM: %w", err)
+	}
+	if len(header.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("unexpected nonce size in envelope header")
+	}
+
+	plaintext, err := gcm.Open(nil, header.Nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt envelope: %w", err)
+	}
+
+	return plaintext, nil
+}
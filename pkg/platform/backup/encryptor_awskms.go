@@ -0,0 +1,53 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// awsKMSWrapper wraps the data encryption key with an AWS KMS key ARN.
+type awsKMSWrapper struct {
+	client *kms.Client
+	keyARN string
+}
+
+func newAWSKMSWrapper(uri string) (*awsKMSWrapper, error) {
+	keyARN := strings.TrimPrefix(uri, "aws-kms://")
+	if keyARN == "" {
+		return nil, fmt.Errorf("aws-kms uri must include a key arn, e.g. aws-kms://arn:aws:kms:...")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load AWS config: %w", err)
+	}
+
+	return &awsKMSWrapper{client: kms.NewFromConfig(cfg), keyARN: keyARN}, nil
+}
+
+func (w *awsKMSWrapper) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	out, err := w.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(w.keyARN),
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms encrypt: %w", err)
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (w *awsKMSWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := w.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(w.keyARN),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	return out.Plaintext, nil
+}
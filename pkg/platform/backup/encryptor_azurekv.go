@@ -0,0 +1,68 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+)
+
+// azureKeyVaultWrapper wraps the data encryption key with a key stored in
+// Azure Key Vault, e.g. azure-kv://myvault.vault.azure.net/keys/mykey.
+type azureKeyVaultWrapper struct {
+	client  *azkeys.Client
+	keyName string
+}
+
+func newAzureKeyVaultWrapper(uri string) (*azureKeyVaultWrapper, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse kms uri %q: %w", uri, err)
+	}
+
+	parts := strings.SplitN(strings.Trim(parsed.Path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] != "keys" || parts[1] == "" {
+		return nil, fmt.Errorf("azure-kv uri must look like azure-kv://<vault>.vault.azure.net/keys/<name>")
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("create Azure default credential: %w", err)
+	}
+
+	client, err := azkeys.NewClient(fmt.Sprintf("https://%s", parsed.Host), cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create Azure Key Vault client: %w", err)
+	}
+
+	return &azureKeyVaultWrapper{client: client, keyName: parts[1]}, nil
+}
+
+func (w *azureKeyVaultWrapper) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	resp, err := w.client.WrapKey(ctx, w.keyName, "", azkeys.KeyOperationParameters{
+		Algorithm: ptrTo(azkeys.EncryptionAlgorithmRSAOAEP256),
+		Value:     dek,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("key vault wrap key: %w", err)
+	}
+	return resp.Result, nil
+}
+
+func (w *azureKeyVaultWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	resp, err := w.client.UnwrapKey(ctx, w.keyName, "", azkeys.KeyOperationParameters{
+		Algorithm: ptrTo(azkeys.EncryptionAlgorithmRSAOAEP256),
+		Value:     wrapped,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("key vault unwrap key: %w", err)
+	}
+	return resp.Result, nil
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,54 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+)
+
+// gcpKMSWrapper wraps the data encryption key with a Cloud KMS key
+// referenced by its full resource name, e.g.
+// projects/p/locations/l/keyRings/r/cryptoKeys/k.
+type gcpKMSWrapper struct {
+	client  *kms.KeyManagementClient
+	keyName string
+}
+
+func newGCPKMSWrapper(uri string) (*gcpKMSWrapper, error) {
+	keyName := strings.TrimPrefix(uri, "gcp-kms://")
+	if keyName == "" {
+		return nil, fmt.Errorf("gcp-kms uri must include a key resource name, e.g. gcp-kms://projects/p/locations/l/keyRings/r/cryptoKeys/k")
+	}
+
+	client, err := kms.NewKeyManagementClient(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("create Cloud KMS client: %w", err)
+	}
+
+	return &gcpKMSWrapper{client: client, keyName: keyName}, nil
+}
+
+func (w *gcpKMSWrapper) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	resp, err := w.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      w.keyName,
+		Plaintext: dek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms encrypt: %w", err)
+	}
+	return resp.Ciphertext, nil
+}
+
+func (w *gcpKMSWrapper) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	resp, err := w.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       w.keyName,
+		Ciphertext: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,90 @@
+package backup
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const passphraseKDFIterations = 600000
+
+// passphraseWrapper wraps the data encryption key with a key derived from a
+// user-supplied passphrase via PBKDF2, for users who don't have (or don't
+// want) a cloud KMS. The salt is stored alongside the wrapped key so the
+// same passphrase can derive it again on restore.
+type passphraseWrapper struct {
+	passphrase string
+}
+
+// newPassphraseWrapper reads the passphrase from the VCLUSTER_BACKUP_PASSPHRASE
+// env var, the same convention used for other sensitive vcluster CLI inputs.
+func newPassphraseWrapper(uri string) (*passphraseWrapper, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse kms uri %q: %w", uri, err)
+	}
+
+	passphrase := parsed.Query().Get("passphrase")
+	if passphrase == "" {
+		passphrase = os.Getenv("VCLUSTER_BACKUP_PASSPHRASE")
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase kms requires VCLUSTER_BACKUP_PASSPHRASE to be set (or ?passphrase= for testing)")
+	}
+
+	return &passphraseWrapper{passphrase: passphrase}, nil
+}
+
+func (w *passphraseWrapper) WrapKey(_ context.Context, dek []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+
+	gcm, err := w.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate wrap nonce: %w", err)
+	}
+
+	// wrapped layout: salt | nonce | ciphertext
+	wrapped := append(append([]byte{}, salt...), nonce...)
+	wrapped = gcm.Seal(wrapped, nonce, dek, nil)
+	return wrapped, nil
+}
+
+func (w *passphraseWrapper) UnwrapKey(_ context.Context, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 16+12 {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	salt, rest := wrapped[:16], wrapped[16:]
+	nonce, ciphertext := rest[:12], rest[12:]
+
+	gcm, err := w.gcm(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func (w *passphraseWrapper) gcm(salt []byte) (cipher.AEAD, error) {
+	key := pbkdf2.Key([]byte(w.passphrase), salt, passphraseKDFIterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init AES cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
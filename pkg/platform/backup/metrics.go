@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	lastSuccessTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "loft_backup_last_success_timestamp",
+		Help: "Unix timestamp of the last successful scheduled backup.",
+	})
+	durationSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "loft_backup_duration_seconds",
+		Help: "How long the last scheduled backup run took, in seconds.",
+	})
+	failuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "loft_backup_failures_total",
+		Help: "Total number of scheduled backup runs that failed.",
+	})
+)
+
+// RunMetrics is what a scheduled backup run reports once it finishes, so
+// that it can be pushed to a Prometheus Pushgateway for scraping -- a
+// CronJob pod doesn't live long enough to be scraped directly.
+type RunMetrics struct {
+	Success          bool
+	DurationSeconds  float64
+	SuccessTimestamp int64
+}
+
+// PushMetrics pushes the metrics for one scheduled backup run to gatewayURL.
+// It is a no-op if gatewayURL is empty, since ad hoc `vcluster platform
+// backup` runs have nowhere to push to and shouldn't fail because of it.
+func PushMetrics(gatewayURL, job string, metrics RunMetrics) error {
+	if gatewayURL == "" {
+		return nil
+	}
+
+	// Add (not Push) only replaces the metrics sent here, so a failed run
+	// leaves the previous run's last-success timestamp in place.
+	durationSeconds.Set(metrics.DurationSeconds)
+	pusher := push.New(gatewayURL, job).Collector(durationSeconds)
+	if metrics.Success {
+		lastSuccessTimestamp.Set(float64(metrics.SuccessTimestamp))
+		pusher = pusher.Collector(lastSuccessTimestamp)
+	} else {
+		failuresTotal.Inc()
+		pusher = pusher.Collector(failuresTotal)
+	}
+
+	if err := pusher.Add(); err != nil {
+		return fmt.Errorf("push metrics to %s: %w", gatewayURL, err)
+	}
+
+	return nil
+}
+
+// RecordEvent emits a Kubernetes Event in namespace recording the outcome
+// of a scheduled backup run, so that `kubectl describe` and Event-watching
+// tooling surfaces failures the same way any other in-cluster job would.
+func RecordEvent(ctx context.Context, kubeClient kubernetes.Interface, namespace string, success bool, message string) error {
+	reason := "BackupSucceeded"
+	eventType := corev1.EventTypeNormal
+	if !success {
+		reason = "BackupFailed"
+		eventType = corev1.EventTypeWarning
+	}
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "platform-backup-",
+			Namespace:    namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "CronJob",
+			Name:      "platform-backup",
+			Namespace: namespace,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		FirstTimestamp: metav1.Now(),
+		LastTimestamp:  metav1.Now(),
+		Count:          1,
+		Source:         corev1.EventSource{Component: "vcluster-platform-backup"},
+	}
+
+	if _, err := kubeClient.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("record backup event: %w", err)
+	}
+
+	return nil
+}
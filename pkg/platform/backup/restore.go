@@ -0,0 +1,70 @@
+package backup
+
+import (
+	"bytes"
+	"fmt"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FromYAML parses a multi-document YAML stream produced by ToYAML back into
+// the concrete typed objects it was built from.
+func FromYAML(data []byte) ([]client.Object, error) {
+	var objects []client.Object
+
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		raw := &unstructured.Unstructured{}
+		if err := decoder.Decode(raw); err != nil {
+			if err.Error() == "EOF" {
+				break
+			}
+			return nil, fmt.Errorf("decode backup document: %w", err)
+		}
+		if len(raw.Object) == 0 {
+			continue
+		}
+
+		obj, err := typed(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// typed converts a generic unstructured document into the concrete type
+// matching its Kind, so that the rest of the restore pipeline can work with
+// strongly typed objects the same way the backup side does.
+func typed(raw *unstructured.Unstructured) (client.Object, error) {
+	var obj client.Object
+	switch raw.GetKind() {
+	case "User":
+		obj = &storagev1.User{}
+	case "Team":
+		obj = &storagev1.Team{}
+	case "AccessKey":
+		obj = &storagev1.AccessKey{}
+	case "SharedSecret":
+		obj = &storagev1.SharedSecret{}
+	case "Cluster":
+		obj = &storagev1.Cluster{}
+	case "ClusterAccountTemplate":
+		obj = &storagev1.ClusterAccountTemplate{}
+	default:
+		return nil, fmt.Errorf("unsupported backup object kind %q", raw.GetKind())
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw.Object, obj); err != nil {
+		return nil, fmt.Errorf("convert %s %q: %w", raw.GetKind(), raw.GetName(), err)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,60 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RetentionOptions bounds how many backups a PruningDestination is allowed
+// to keep. A zero value means that dimension is not enforced.
+type RetentionOptions struct {
+	KeepLast int
+	KeepDays int
+}
+
+// Prune removes backups from dest that fall outside the retention window.
+// A backup is kept if either rule says to keep it -- it is one of the most
+// recent KeepLast backups, or it is not yet older than KeepDays -- so that
+// the more permissive of the two bounds wins. It returns the number of
+// backups it deleted.
+func Prune(ctx context.Context, dest Destination, opts RetentionOptions, log func(string)) (int, error) {
+	if opts.KeepLast <= 0 && opts.KeepDays <= 0 {
+		return 0, nil
+	}
+
+	pruning, ok := dest.(PruningDestination)
+	if !ok {
+		return 0, fmt.Errorf("destination does not support retention (List/Delete)")
+	}
+
+	objects, err := pruning.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list existing backups: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Timestamp.After(objects[j].Timestamp) })
+
+	cutoff := time.Time{}
+	if opts.KeepDays > 0 {
+		cutoff = time.Now().Add(-time.Duration(opts.KeepDays) * 24 * time.Hour)
+	}
+
+	deleted := 0
+	for i, obj := range objects {
+		keepByCount := opts.KeepLast > 0 && i < opts.KeepLast
+		keepByAge := opts.KeepDays > 0 && obj.Timestamp.After(cutoff)
+		if keepByCount || keepByAge {
+			continue
+		}
+
+		if err := pruning.Delete(ctx, obj.Key); err != nil {
+			return deleted, fmt.Errorf("delete backup %s: %w", obj.Key, err)
+		}
+		log(fmt.Sprintf("Pruned backup %s", obj.Key))
+		deleted++
+	}
+
+	return deleted, nil
+}
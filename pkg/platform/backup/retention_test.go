@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakePruningDestination is an in-memory PruningDestination for exercising
+// Prune without a real object store.
+type fakePruningDestination struct {
+	objects []ObjectInfo
+	deleted []string
+}
+
+func (d *fakePruningDestination) Write(context.Context, []byte) (string, error) {
+	return "", nil
+}
+
+func (d *fakePruningDestination) List(context.Context) ([]ObjectInfo, error) {
+	return d.objects, nil
+}
+
+func (d *fakePruningDestination) Delete(_ context.Context, key string) error {
+	d.deleted = append(d.deleted, key)
+	return nil
+}
+
+func objectsAged(days ...int) []ObjectInfo {
+	// Prune measures age against time.Now(), so the fixture has to as well.
+	now := time.Now()
+	objects := make([]ObjectInfo, len(days))
+	for i, d := range days {
+		objects[i] = ObjectInfo{
+			Key:       fmt.Sprintf("backup-%d", i),
+			Timestamp: now.Add(-time.Duration(d) * 24 * time.Hour),
+		}
+	}
+	return objects
+}
+
+func TestPrune_KeepsWheneverEitherRuleSaysKeep(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        RetentionOptions
+		ages        []int // days old, newest first
+		wantDeleted int
+	}{
+		{
+			name:        "union keeps newest-30-days even past keep-last",
+			opts:        RetentionOptions{KeepLast: 7, KeepDays: 30},
+			ages:        daysRange(60), // 60 daily backups, 0..59 days old
+			wantDeleted: 30,            // the newest 30 days survive even though keep-last is only 7
+		},
+		{
+			name:        "keep-last only",
+			opts:        RetentionOptions{KeepLast: 2},
+			ages:        []int{0, 1, 2, 3},
+			wantDeleted: 2,
+		},
+		{
+			name:        "keep-days only",
+			opts:        RetentionOptions{KeepDays: 10},
+			ages:        []int{1, 5, 20, 40},
+			wantDeleted: 2,
+		},
+		{
+			name:        "neither set prunes nothing",
+			opts:        RetentionOptions{},
+			ages:        []int{1, 100, 1000},
+			wantDeleted: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := &fakePruningDestination{objects: objectsAged(tt.ages...)}
+			deleted, err := Prune(context.Background(), dest, tt.opts, func(string) {})
+			if err != nil {
+				t.Fatalf("Prune: %v", err)
+			}
+			if deleted != tt.wantDeleted {
+				t.Fatalf("Prune deleted %d objects, want %d", deleted, tt.wantDeleted)
+			}
+			if len(dest.deleted) != tt.wantDeleted {
+				t.Fatalf("dest.Delete called %d times, want %d", len(dest.deleted), tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func daysRange(n int) []int {
+	ages := make([]int, n)
+	for i := range ages {
+		ages[i] = i
+	}
+	return ages
+}
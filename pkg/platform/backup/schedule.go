@@ -0,0 +1,174 @@
+package backup
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduleOptions configures the CronJob a `platform backup schedule`
+// install creates.
+type ScheduleOptions struct {
+	Cron               string
+	Image              string
+	Namespace          string
+	Destination        string
+	KMS                string
+	Skip               []string
+	KeepLast           int
+	KeepDays           int
+	MetricsPushgateway string
+}
+
+const CronJobName = "platform-backup"
+
+// ServiceAccountName is the identity the schedule's CronJob runs as. It is
+// bound to ClusterRoleName by NewClusterRoleBinding, which grants it the
+// read access backup.All needs and the right to create Events.
+const ServiceAccountName = "loft-backup"
+
+// ClusterRoleName grants ServiceAccountName read access to the resources
+// backup.All collects, and create access to Events for RecordEvent. It is
+// cluster-scoped because those resources are cluster-scoped themselves.
+const ClusterRoleName = "loft-backup"
+
+// storageAPIGroup is the API group of the storagev1 resources a backup
+// collects (see backup.Resources).
+const storageAPIGroup = "storage.loft.sh"
+
+// NewServiceAccount builds the identity the schedule's CronJob runs as.
+func NewServiceAccount(namespace string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ServiceAccountName,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "vcluster-platform-backup",
+				"app.kubernetes.io/component": "backup-schedule",
+			},
+		},
+	}
+}
+
+// NewClusterRole grants read access to the resources a backup collects, and
+// create access to Events, so that ServiceAccountName can run `vcluster
+// platform backup` unattended and record each run's outcome.
+func NewClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ClusterRoleName,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "vcluster-platform-backup",
+				"app.kubernetes.io/component": "backup-schedule",
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{storageAPIGroup},
+				Resources: []string{"users", "teams", "accesskeys", "sharedsecrets", "clusters", "clusteraccounttemplates"},
+				Verbs:     []string{"get", "list"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+}
+
+// NewClusterRoleBinding binds ClusterRoleName to ServiceAccountName in
+// namespace.
+func NewClusterRoleBinding(namespace string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: ClusterRoleName,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "vcluster-platform-backup",
+				"app.kubernetes.io/component": "backup-schedule",
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     ClusterRoleName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      ServiceAccountName,
+				Namespace: namespace,
+			},
+		},
+	}
+}
+
+// NewCronJob builds the CronJob that periodically re-runs `vcluster
+// platform backup` with opts baked in as flags, the same way a user would
+// invoke it by hand, plus the retention flags that make it prune old
+// backups at the destination after each run.
+func NewCronJob(opts ScheduleOptions) *batchv1.CronJob {
+	args := []string{"platform", "backup", "--namespace", opts.Namespace}
+	if opts.Destination != "" {
+		args = append(args, "--destination", opts.Destination)
+	}
+	if opts.KMS != "" {
+		args = append(args, "--kms", opts.KMS)
+	}
+	for _, skip := range opts.Skip {
+		args = append(args, "--skip", skip)
+	}
+	if opts.KeepLast > 0 {
+		args = append(args, "--keep-last", fmt.Sprintf("%d", opts.KeepLast))
+	}
+	if opts.KeepDays > 0 {
+		args = append(args, "--keep-days", fmt.Sprintf("%d", opts.KeepDays))
+	}
+	if opts.MetricsPushgateway != "" {
+		args = append(args, "--metrics-pushgateway", opts.MetricsPushgateway)
+	}
+
+	return &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CronJobName,
+			Namespace: opts.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":      "vcluster-platform-backup",
+				"app.kubernetes.io/component": "backup-schedule",
+			},
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   opts.Cron,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptrTo(int32(3)),
+			FailedJobsHistoryLimit:     ptrTo(int32(3)),
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptrTo(int32(2)),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								"app.kubernetes.io/name":      "vcluster-platform-backup",
+								"app.kubernetes.io/component": "backup-schedule",
+							},
+						},
+						Spec: corev1.PodSpec{
+							RestartPolicy:      corev1.RestartPolicyNever,
+							ServiceAccountName: ServiceAccountName,
+							Containers: []corev1.Container{
+								{
+									Name:  "backup",
+									Image: opts.Image,
+									Args:  args,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,185 @@
+// Package restore applies the objects produced by a `vcluster platform
+// backup` back onto a platform install. It is the inverse of
+// github.com/loft-sh/vcluster/pkg/platform/backup.
+package restore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ExistingMode controls how a restore reacts when an object it wants to
+// create already exists in the target cluster.
+type ExistingMode string
+
+const (
+	ExistingSkip      ExistingMode = "skip"
+	ExistingOverwrite ExistingMode = "overwrite"
+	ExistingFail      ExistingMode = "fail"
+)
+
+// Options configures a restore run.
+type Options struct {
+	// Skip lists the resource kinds (see backup.Resources) that should not
+	// be restored even if they are present in the backup.
+	Skip []string
+	// Existing controls what happens when an object to restore already
+	// exists in the target cluster.
+	Existing ExistingMode
+}
+
+// Result summarizes the outcome of a restore run.
+type Result struct {
+	Created int
+	Skipped int
+	Failed  int
+}
+
+// HasFailures reports whether any object failed to restore.
+func (r *Result) HasFailures() bool {
+	return r.Failed > 0
+}
+
+// All restores the given objects, which are expected to be in the same
+// order backup.All produced them in, against the target cluster. Owner
+// references between objects (e.g. an AccessKey owned by a Team) are
+// rewritten to point at the UIDs assigned by the target cluster rather than
+// the UIDs recorded in the backup.
+func All(ctx context.Context, c client.Client, objects []client.Object, opts Options, log func(string)) (*Result, error) {
+	skip := make(map[string]bool, len(opts.Skip))
+	for _, s := range opts.Skip {
+		skip[s] = true
+	}
+
+	existing := opts.Existing
+	if existing == "" {
+		existing = ExistingSkip
+	}
+
+	result := &Result{}
+	uids := map[types.UID]types.UID{}
+
+	for _, obj := range objects {
+		resource, err := resourceOf(obj)
+		if err != nil {
+			return result, err
+		}
+		if skip[resource] {
+			continue
+		}
+
+		oldUID := obj.GetUID()
+		rewriteOwnerReferences(obj, uids)
+
+		// the object we send to the API server must not carry over
+		// server-assigned fields from the backup.
+		obj.SetUID("")
+		obj.SetResourceVersion("")
+
+		newUID, err := create(ctx, c, obj, existing)
+		switch {
+		case err == nil:
+			log(fmt.Sprintf("Created %s %q", resource, obj.GetName()))
+			result.Created++
+			if oldUID != "" {
+				uids[oldUID] = newUID
+			}
+		case errors.Is(err, errSkipped):
+			log(fmt.Sprintf("Skipped %s %q (already exists)", resource, obj.GetName()))
+			result.Skipped++
+			if oldUID != "" {
+				uids[oldUID] = newUID
+			}
+		default:
+			log(fmt.Sprintf("Failed to restore %s %q: %v", resource, obj.GetName(), err))
+			result.Failed++
+			if existing == ExistingFail {
+				return result, fmt.Errorf("restore %s %q: %w", resource, obj.GetName(), err)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+var errSkipped = errors.New("object already exists")
+
+// resourceOf maps an object back to the backup.Resources name it belongs
+// to, so that --skip and log output can refer to resources the same way
+// the backup side does.
+func resourceOf(obj client.Object) (string, error) {
+	switch obj.(type) {
+	case *storagev1.User:
+		return "users", nil
+	case *storagev1.Team:
+		return "teams", nil
+	case *storagev1.AccessKey:
+		return "accesskeys", nil
+	case *storagev1.SharedSecret:
+		return "sharedsecrets", nil
+	case *storagev1.Cluster:
+		return "clusters", nil
+	case *storagev1.ClusterAccountTemplate:
+		return "clusteraccounttemplates", nil
+	default:
+		return "", fmt.Errorf("unsupported restore object type %T", obj)
+	}
+}
+
+// create applies the conflict-resolution mode for a single object: it
+// creates the object if absent, and either updates, skips or fails if an
+// object with the same name already exists. It returns the UID the object
+// ends up with in the target cluster -- whether newly created, updated in
+// place, or (on skip) the pre-existing object's -- so that All can rewrite
+// dependents' owner references against it even when this object itself
+// wasn't created.
+func create(ctx context.Context, c client.Client, obj client.Object, existing ExistingMode) (types.UID, error) {
+	err := c.Create(ctx, obj)
+	if err == nil {
+		return obj.GetUID(), nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	current := obj.DeepCopyObject().(client.Object)
+	if getErr := c.Get(ctx, client.ObjectKeyFromObject(obj), current); getErr != nil {
+		return "", fmt.Errorf("get existing object: %w", getErr)
+	}
+
+	switch existing {
+	case ExistingOverwrite:
+		obj.SetResourceVersion(current.GetResourceVersion())
+		obj.SetUID(current.GetUID())
+		if err := c.Update(ctx, obj); err != nil {
+			return "", fmt.Errorf("update existing object: %w", err)
+		}
+		return obj.GetUID(), nil
+	case ExistingFail:
+		return "", fmt.Errorf("object already exists: %w", err)
+	default: // ExistingSkip
+		return current.GetUID(), errSkipped
+	}
+}
+
+// rewriteOwnerReferences resolves any owner reference on obj whose UID is
+// known to have changed during this restore run.
+func rewriteOwnerReferences(obj client.Object, uids map[types.UID]types.UID) {
+	refs := obj.GetOwnerReferences()
+	if len(refs) == 0 {
+		return
+	}
+
+	for i, ref := range refs {
+		if newUID, ok := uids[ref.UID]; ok {
+			refs[i].UID = newUID
+		}
+	}
+	obj.SetOwnerReferences(refs)
+}
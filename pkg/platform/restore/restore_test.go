@@ -0,0 +1,135 @@
+package restore
+
+import (
+	"context"
+	"testing"
+
+	storagev1 "github.com/loft-sh/api/v4/pkg/apis/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := storagev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add storagev1 to scheme: %v", err)
+	}
+	return scheme
+}
+
+// backedUpTeamAndAccessKey builds the two objects a backup would have
+// produced: a Team and an AccessKey owned by it, both carrying the UIDs
+// they had at backup time.
+func backedUpTeamAndAccessKey(teamUID types.UID) (*storagev1.Team, *storagev1.AccessKey) {
+	team := &storagev1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-team", UID: teamUID},
+	}
+	accessKey := &storagev1.AccessKey{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "some-team-key",
+			UID:  "old-access-key-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: storagev1.SchemeGroupVersion.String(), Kind: "Team", Name: "some-team", UID: teamUID},
+			},
+		},
+	}
+	return team, accessKey
+}
+
+func TestAll_ExistingSkip_RewritesOwnerRefToExistingUID(t *testing.T) {
+	existingTeam := &storagev1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-team", UID: "real-team-uid"},
+	}
+	backedUpTeam, accessKey := backedUpTeamAndAccessKey("old-team-uid")
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(existingTeam).Build()
+
+	result, err := All(context.Background(), c, []client.Object{backedUpTeam, accessKey}, Options{}, func(string) {})
+	if err != nil {
+		t.Fatalf("All returned error: %v", err)
+	}
+	if result.Skipped != 1 || result.Created != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var created storagev1.AccessKey
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "some-team-key"}, &created); err != nil {
+		t.Fatalf("get created access key: %v", err)
+	}
+	if len(created.OwnerReferences) != 1 || created.OwnerReferences[0].UID != "real-team-uid" {
+		t.Fatalf("owner reference UID not rewritten to existing team's UID, got %+v", created.OwnerReferences)
+	}
+}
+
+func TestAll_ExistingOverwrite_RewritesOwnerRefAndUpdatesObject(t *testing.T) {
+	existingTeam := &storagev1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-team", UID: "real-team-uid"},
+	}
+	backedUpTeam, accessKey := backedUpTeamAndAccessKey("old-team-uid")
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(existingTeam).Build()
+
+	result, err := All(context.Background(), c, []client.Object{backedUpTeam, accessKey}, Options{Existing: ExistingOverwrite}, func(string) {})
+	if err != nil {
+		t.Fatalf("All returned error: %v", err)
+	}
+	if result.Created != 2 || result.Skipped != 0 || result.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var updatedTeam storagev1.Team
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "some-team"}, &updatedTeam); err != nil {
+		t.Fatalf("get updated team: %v", err)
+	}
+	if updatedTeam.UID != "real-team-uid" {
+		t.Fatalf("overwrite changed the team's UID, got %q", updatedTeam.UID)
+	}
+
+	var createdAccessKey storagev1.AccessKey
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "some-team-key"}, &createdAccessKey); err != nil {
+		t.Fatalf("get created access key: %v", err)
+	}
+	if len(createdAccessKey.OwnerReferences) != 1 || createdAccessKey.OwnerReferences[0].UID != "real-team-uid" {
+		t.Fatalf("owner reference UID not rewritten to existing team's UID, got %+v", createdAccessKey.OwnerReferences)
+	}
+}
+
+func TestAll_ExistingFail_FailsAndReturnsError(t *testing.T) {
+	existingTeam := &storagev1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-team", UID: "real-team-uid"},
+	}
+	backedUpTeam, _ := backedUpTeamAndAccessKey("old-team-uid")
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(existingTeam).Build()
+
+	result, err := All(context.Background(), c, []client.Object{backedUpTeam}, Options{Existing: ExistingFail}, func(string) {})
+	if err == nil {
+		t.Fatal("expected All to return an error when an object conflicts under --existing=fail")
+	}
+	if !result.HasFailures() {
+		t.Fatalf("expected result to record a failure, got %+v", result)
+	}
+}
+
+func TestAll_SkipsResourceKindsInOptions(t *testing.T) {
+	team := &storagev1.Team{ObjectMeta: metav1.ObjectMeta{Name: "some-team", UID: "team-uid"}}
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+
+	result, err := All(context.Background(), c, []client.Object{team}, Options{Skip: []string{"teams"}}, func(string) {})
+	if err != nil {
+		t.Fatalf("All returned error: %v", err)
+	}
+	if result.Created != 0 || result.Skipped != 0 || result.Failed != 0 {
+		t.Fatalf("expected a skipped resource kind to be a no-op, got %+v", result)
+	}
+
+	var got storagev1.Team
+	if err := c.Get(context.Background(), client.ObjectKey{Name: "some-team"}, &got); err == nil {
+		t.Fatal("expected team to not be restored since its kind was in Options.Skip")
+	}
+}